@@ -6,6 +6,7 @@
 //	fmt.Println(color.RGB(255, 0, 128, "真彩色"))
 //	fmt.Println(color.Index(202, "8bit橙色"), color.BgIndex(27, "8bit海蓝"))
 //	fmt.Println(color.Wrap("组合", color.FgBlack, color.BoldAttr, color.UnderlineAttr))
+//	fmt.Println(color.Sprintft("<red>错误:</> {count=%d}", 3))
 package color
 
 import (
@@ -132,6 +133,7 @@ func Wrap(s string, attrs ...Attr) string {
 // ----------- 8bit(256色) 与 24bit 真彩 -----------
 
 // Index 返回 8bit 前景色（0-255），示例：Index(202, "橙色")
+// 若当前终端检测到的颜色级别低于 Level256，会自动降级为最接近的 16 色。
 func Index(idx int, s string) string {
 	if !enabled.Load() || s == "" {
 		return s
@@ -141,10 +143,14 @@ func Index(idx int, s string) string {
 	} else if idx > 255 {
 		idx = 255
 	}
+	if effectiveLevel() < Level256 {
+		r, g, b := approxIndexRGB(idx)
+		return Wrap(s, nearestAttr16(r, g, b, false))
+	}
 	return fmt.Sprintf("%s38;5;%dm%s%s", esc, idx, s, reset)
 }
 
-// BgIndex 返回 8bit 背景色（0-255）
+// BgIndex 返回 8bit 背景色（0-255），降级规则同 Index。
 func BgIndex(idx int, s string) string {
 	if !enabled.Load() || s == "" {
 		return s
@@ -154,25 +160,44 @@ func BgIndex(idx int, s string) string {
 	} else if idx > 255 {
 		idx = 255
 	}
+	if effectiveLevel() < Level256 {
+		r, g, b := approxIndexRGB(idx)
+		return Wrap(s, nearestAttr16(r, g, b, true))
+	}
 	return fmt.Sprintf("%s48;5;%dm%s%s", esc, idx, s, reset)
 }
 
-// RGB 使用 24bit 真彩前景色，r/g/b 范围 0-255
+// RGB 使用 24bit 真彩前景色，r/g/b 范围 0-255。
+// 若终端不支持真彩，会按检测到的级别降级为 256 色或 16 色。
 func RGB(r, g, b int, s string) string {
 	if !enabled.Load() || s == "" {
 		return s
 	}
 	r, g, b = clamp255(r), clamp255(g), clamp255(b)
-	return fmt.Sprintf("%s38;2;%d;%d;%dm%s%s", esc, r, g, b, s, reset)
+	switch effectiveLevel() {
+	case LevelTrueColor:
+		return fmt.Sprintf("%s38;2;%d;%d;%dm%s%s", esc, r, g, b, s, reset)
+	case Level256:
+		return Index(NearestIndex(r, g, b), s)
+	default:
+		return Wrap(s, nearestAttr16(r, g, b, false))
+	}
 }
 
-// BgRGB 使用 24bit 真彩背景色
+// BgRGB 使用 24bit 真彩背景色，降级规则同 RGB。
 func BgRGB(r, g, b int, s string) string {
 	if !enabled.Load() || s == "" {
 		return s
 	}
 	r, g, b = clamp255(r), clamp255(g), clamp255(b)
-	return fmt.Sprintf("%s48;2;%d;%d;%dm%s%s", esc, r, g, b, s, reset)
+	switch effectiveLevel() {
+	case LevelTrueColor:
+		return fmt.Sprintf("%s48;2;%d;%d;%dm%s%s", esc, r, g, b, s, reset)
+	case Level256:
+		return BgIndex(NearestIndex(r, g, b), s)
+	default:
+		return Wrap(s, nearestAttr16(r, g, b, true))
+	}
 }
 
 func clamp255(v int) int {
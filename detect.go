@@ -0,0 +1,147 @@
+package color
+
+import (
+	"io"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"golang.org/x/term"
+)
+
+// Level 表示目标终端对颜色的支持级别，由浅到深。
+type Level int
+
+const (
+	LevelNone      Level = iota // 不支持颜色（非终端、NO_COLOR、CLICOLOR=0 等）
+	Level16                     // 支持标准/高亮 16 色
+	Level256                    // 支持 8bit(256色)
+	LevelTrueColor              // 支持 24bit 真彩（$COLORTERM=truecolor/24bit）
+)
+
+// currentLevel 缓存包初始化时针对 os.Stdout 检测到的颜色级别，
+// RGB/Index 据此决定是否需要降级输出。
+var currentLevel atomic.Int32
+
+func init() {
+	lvl := SupportsColor(stdoutFd())
+	currentLevel.Store(int32(lvl))
+	if lvl == LevelNone {
+		Disable()
+	}
+}
+
+func effectiveLevel() Level { return Level(currentLevel.Load()) }
+
+func stdoutFd() uintptr { return os.Stdout.Fd() }
+
+// SupportsColor 检测文件描述符 fd 所在终端的颜色支持级别，遵循生态惯例：
+//
+//   - NO_COLOR（任意值）或 CLICOLOR=0：强制 LevelNone；
+//   - FORCE_COLOR（非空且非 "0"）或 CLICOLOR_FORCE（任意值）：即使 fd 不是
+//     终端也强制按 TERM/COLORTERM 推断出的级别启用；
+//   - 其余情况下，fd 不是终端时返回 LevelNone，否则按 $COLORTERM/$TERM 推断。
+func SupportsColor(fd uintptr) Level {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("CLICOLOR") == "0" {
+		return LevelNone
+	}
+	forced := isForced()
+	if !forced && !term.IsTerminal(int(fd)) {
+		return LevelNone
+	}
+	return levelFromEnv(forced)
+}
+
+func isForced() bool {
+	if v := os.Getenv("FORCE_COLOR"); v != "" && v != "0" {
+		return true
+	}
+	return os.Getenv("CLICOLOR_FORCE") != ""
+}
+
+func levelFromEnv(forced bool) Level {
+	switch os.Getenv("COLORTERM") {
+	case "truecolor", "24bit":
+		return LevelTrueColor
+	}
+	t := os.Getenv("TERM")
+	switch {
+	case strings.Contains(t, "256color"):
+		return Level256
+	case t == "" || t == "dumb":
+		if forced {
+			return Level16
+		}
+		return LevelNone
+	default:
+		return Level16
+	}
+}
+
+// EnableFor 判断是否应该为 w 启用颜色输出（按 w 自身的 fd 检测，
+// 而非包级别的全局开关），供需要按流分别决定的场景使用，
+// 例如同时写向一个终端和一个被重定向到文件的 Writer。
+// w 没有 Fd() 方法时无法检测，此时返回包级别开关的当前状态。
+func EnableFor(w io.Writer) bool {
+	fd, ok := fdOf(w)
+	if !ok {
+		return enabled.Load()
+	}
+	return SupportsColor(fd) != LevelNone
+}
+
+func fdOf(w io.Writer) (uintptr, bool) {
+	type fdWriter interface{ Fd() uintptr }
+	fw, ok := w.(fdWriter)
+	if !ok {
+		return 0, false
+	}
+	return fw.Fd(), true
+}
+
+// approxIndexRGB 把 8bit 调色板下标还原成近似 RGB，供 Index/BgIndex
+// 在终端不支持 256 色时降级使用。
+func approxIndexRGB(idx int) (r, g, b int) {
+	switch {
+	case idx < 16:
+		levels := [16][3]int{
+			{0, 0, 0}, {128, 0, 0}, {0, 128, 0}, {128, 128, 0},
+			{0, 0, 128}, {128, 0, 128}, {0, 128, 128}, {192, 192, 192},
+			{128, 128, 128}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+			{0, 0, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+		}
+		v := levels[idx]
+		return v[0], v[1], v[2]
+	case idx < 232:
+		idx -= 16
+		steps := [6]int{0, 95, 135, 175, 215, 255}
+		return steps[idx/36], steps[(idx/6)%6], steps[idx%6]
+	default:
+		v := 8 + 10*(idx-232)
+		return v, v, v
+	}
+}
+
+// nearestAttr16 把一个 RGB 值降级为最接近的标准/高亮 16 色 Attr。
+func nearestAttr16(r, g, b int, bg bool) Attr {
+	best, bestDist := 0, -1
+	for i := 0; i < 8; i++ {
+		rr, gg, bb := (i&1)*255, (i>>1&1)*255, (i>>2&1)*255
+		d := (r-rr)*(r-rr) + (g-gg)*(g-gg) + (b-bb)*(b-bb)
+		if bestDist < 0 || d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	bright := r+g+b > 255*3/2
+	base := FgBlack
+	if bg {
+		base = BgBlack
+	}
+	if bright {
+		base = FgBrightBlack
+		if bg {
+			base = BgBrightBlack
+		}
+	}
+	return base + Attr(best)
+}
@@ -0,0 +1,22 @@
+package color
+
+import "testing"
+
+func TestNearestAttr16(t *testing.T) {
+	cases := []struct {
+		r, g, b int
+		bg      bool
+		want    Attr
+	}{
+		{255, 0, 0, false, FgRed},
+		{0, 0, 255, false, FgBlue},
+		{0, 255, 0, false, FgGreen},
+		{255, 0, 0, true, BgRed},
+		{0, 0, 255, true, BgBlue},
+	}
+	for _, c := range cases {
+		if got := nearestAttr16(c.r, c.g, c.b, c.bg); got != c.want {
+			t.Errorf("nearestAttr16(%d,%d,%d,%v) = %v, want %v", c.r, c.g, c.b, c.bg, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package color
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Hex 解析形如 "#RRGGBB"、"#RGB"、"RRGGBB" 的十六进制颜色串，
+// 以前景真彩色包裹 s；实际输出仍会按 SupportsColor 检测到的级别自动降级。
+func Hex(hex string, s string) (string, error) {
+	r, g, b, err := parseHex(hex)
+	if err != nil {
+		return "", err
+	}
+	return RGB(r, g, b, s), nil
+}
+
+// BgHex 是 Hex 的背景色版本。
+func BgHex(hex string, s string) (string, error) {
+	r, g, b, err := parseHex(hex)
+	if err != nil {
+		return "", err
+	}
+	return BgRGB(r, g, b, s), nil
+}
+
+func parseHex(hex string) (r, g, b int, err error) {
+	h := strings.TrimPrefix(hex, "#")
+	switch len(h) {
+	case 3:
+		h = string([]byte{h[0], h[0], h[1], h[1], h[2], h[2]})
+	case 6:
+	default:
+		return 0, 0, 0, fmt.Errorf("color: invalid hex color %q", hex)
+	}
+	v, err := strconv.ParseUint(h, 16, 32)
+	if err != nil {
+		return 0, 0, 0, fmt.Errorf("color: invalid hex color %q: %w", hex, err)
+	}
+	return int(v >> 16 & 0xff), int(v >> 8 & 0xff), int(v & 0xff), nil
+}
+
+// Named 按 CSS/X11 颜色名称（如 "coral"、"rebeccapurple"）包裹 s 的前景色。
+// 名称不区分大小写，未收录时返回错误。
+func Named(name string, s string) (string, error) {
+	rgb, ok := namedColors[strings.ToLower(name)]
+	if !ok {
+		return "", fmt.Errorf("color: unknown color name %q", name)
+	}
+	return RGB(rgb[0], rgb[1], rgb[2], s), nil
+}
+
+// BgNamed 是 Named 的背景色版本。
+func BgNamed(name string, s string) (string, error) {
+	rgb, ok := namedColors[strings.ToLower(name)]
+	if !ok {
+		return "", fmt.Errorf("color: unknown color name %q", name)
+	}
+	return BgRGB(rgb[0], rgb[1], rgb[2], s), nil
+}
+
+// NearestIndex 把 24bit RGB 映射到最接近的 xterm 256 色下标。
+// 依次比较 16 个基础色、6x6x6 色立方的 216 个条目（分量取值
+// {0,95,135,175,215,255}）以及 24 级灰阶（8+10*i，i=0..23），
+// 取 RGB 欧氏距离平方最小的一个。
+func NearestIndex(r, g, b int) int {
+	r, g, b = clamp255(r), clamp255(g), clamp255(b)
+
+	best, bestDist := 0, -1
+	consider := func(idx, cr, cg, cb int) {
+		d := (r-cr)*(r-cr) + (g-cg)*(g-cg) + (b-cb)*(b-cb)
+		if bestDist < 0 || d < bestDist {
+			best, bestDist = idx, d
+		}
+	}
+
+	base16 := [16][3]int{
+		{0, 0, 0}, {128, 0, 0}, {0, 128, 0}, {128, 128, 0},
+		{0, 0, 128}, {128, 0, 128}, {0, 128, 128}, {192, 192, 192},
+		{128, 128, 128}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+		{0, 0, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+	}
+	for i, c := range base16 {
+		consider(i, c[0], c[1], c[2])
+	}
+
+	steps := [6]int{0, 95, 135, 175, 215, 255}
+	for ri, rv := range steps {
+		for gi, gv := range steps {
+			for bi, bv := range steps {
+				consider(16+36*ri+6*gi+bi, rv, gv, bv)
+			}
+		}
+	}
+
+	for i := 0; i < 24; i++ {
+		v := 8 + 10*i
+		consider(232+i, v, v, v)
+	}
+
+	return best
+}
@@ -0,0 +1,48 @@
+package color
+
+import "testing"
+
+func TestParseHex(t *testing.T) {
+	cases := []struct {
+		in      string
+		r, g, b int
+		wantErr bool
+	}{
+		{"#ff8800", 255, 136, 0, false},
+		{"ff8800", 255, 136, 0, false},
+		{"#f80", 255, 136, 0, false},
+		{"#ggg", 0, 0, 0, true},
+		{"#ff88", 0, 0, 0, true},
+	}
+	for _, c := range cases {
+		r, g, b, err := parseHex(c.in)
+		if c.wantErr {
+			if err == nil {
+				t.Errorf("parseHex(%q): expected error", c.in)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseHex(%q): unexpected error: %v", c.in, err)
+			continue
+		}
+		if r != c.r || g != c.g || b != c.b {
+			t.Errorf("parseHex(%q) = %d,%d,%d want %d,%d,%d", c.in, r, g, b, c.r, c.g, c.b)
+		}
+	}
+}
+
+func TestNearestIndex(t *testing.T) {
+	if idx := NearestIndex(0, 0, 0); idx != 0 {
+		t.Errorf("NearestIndex(black) = %d, want 0", idx)
+	}
+	if idx := NearestIndex(255, 255, 255); idx != 231 && idx != 15 {
+		t.Errorf("NearestIndex(white) = %d, want 231 or 15", idx)
+	}
+}
+
+func TestNamedUnknown(t *testing.T) {
+	if _, err := Named("not-a-real-color", "x"); err == nil {
+		t.Fatal("expected error for unknown color name")
+	}
+}
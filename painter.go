@@ -0,0 +1,84 @@
+package color
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// Painter 是一个 io.Writer，把写入的内容按行拆分并逐行包裹给定的 SGR 属性，
+// 保证重置码总是落在换行符之前（有些终端在遇到换行时才会重置属性，
+// 整段一起包裹会导致跨行的颜色状态不可控）。典型用法是给子进程的
+// stderr 染色：
+//
+//	cmd.Stderr = color.NewPainter(os.Stderr, color.FgRed)
+//
+// 尚未遇到换行符的残留内容会缓冲起来，直到下一次 Write 补齐或调用 Flush。
+// Painter 可安全并发使用。
+type Painter struct {
+	mu     sync.Mutex
+	w      io.Writer
+	prefix string
+	buf    bytes.Buffer
+}
+
+// NewPainter 创建一个 Painter，把写入的每一行用 attrs 对应的 SGR 包裹后转发给 w。
+func NewPainter(w io.Writer, attrs ...Attr) *Painter {
+	return &Painter{w: w, prefix: SprintAttr(attrs...)}
+}
+
+// Write 实现 io.Writer，按 '\n' 切分并逐行染色输出。
+func (p *Painter) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	n := len(b)
+	for {
+		idx := bytes.IndexByte(b, '\n')
+		if idx < 0 {
+			p.buf.Write(b)
+			return n, nil
+		}
+		p.buf.Write(b[:idx])
+		if err := p.flushLocked(true); err != nil {
+			return n, err
+		}
+		b = b[idx+1:]
+	}
+}
+
+// Flush 把尚未遇到换行符的残留内容写出（不补换行符），适合在子进程退出、
+// 确认不再有更多数据时调用，避免最后一个半行丢失。
+func (p *Painter) Flush() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.buf.Len() == 0 {
+		return nil
+	}
+	return p.flushLocked(false)
+}
+
+func (p *Painter) flushLocked(withNewline bool) error {
+	line := p.buf.Bytes()
+	if !enabled.Load() || p.prefix == "" {
+		if _, err := p.w.Write(line); err != nil {
+			return err
+		}
+	} else {
+		if _, err := io.WriteString(p.w, p.prefix); err != nil {
+			return err
+		}
+		if _, err := p.w.Write(line); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(p.w, reset); err != nil {
+			return err
+		}
+	}
+	p.buf.Reset()
+	if withNewline {
+		_, err := p.w.Write([]byte{'\n'})
+		return err
+	}
+	return nil
+}
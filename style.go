@@ -0,0 +1,98 @@
+package color
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Style 表示一组可链式组合的 SGR 属性，例如：
+//
+//	s := color.New(color.FgRed).Add(color.BoldAttr).Background(color.BgBlue)
+//	fmt.Println(s.Sprint("错误"))
+//
+// 拼装好的 SGR 前缀会缓存在 Style 内部，只在属性变化后的第一次使用时
+// 重新计算，适合像日志库那样预先声明好再反复打印。
+type Style struct {
+	attrs  []Attr
+	prefix string
+}
+
+// New 创建一个携带给定属性的 Style，可继续通过 Add/Background 链式追加。
+func New(attrs ...Attr) *Style {
+	return &Style{attrs: append([]Attr(nil), attrs...)}
+}
+
+// Add 追加一个或多个属性（前景色/样式/背景色皆可），返回自身以便链式调用。
+func (s *Style) Add(attrs ...Attr) *Style {
+	s.attrs = append(s.attrs, attrs...)
+	s.prefix = ""
+	return s
+}
+
+// Background 追加背景色属性；与 Add 等价，单独提供是为了让调用处语义更清晰。
+func (s *Style) Background(attr Attr) *Style {
+	return s.Add(attr)
+}
+
+// build 返回并按需缓存该 Style 对应的 SGR 前缀串。
+func (s *Style) build() string {
+	if s.prefix == "" && len(s.attrs) > 0 {
+		s.prefix = SprintAttr(s.attrs...)
+	}
+	return s.prefix
+}
+
+func (s *Style) wrap(str string) string {
+	if !enabled.Load() || str == "" {
+		return str
+	}
+	prefix := s.build()
+	if prefix == "" {
+		return str
+	}
+	return prefix + str + reset
+}
+
+// Sprint 按 fmt.Sprint 规则拼接参数后应用该样式。
+func (s *Style) Sprint(a ...interface{}) string {
+	return s.wrap(fmt.Sprint(a...))
+}
+
+// Sprintf 按 fmt.Sprintf 规则格式化参数后应用该样式。
+func (s *Style) Sprintf(format string, a ...interface{}) string {
+	return s.wrap(fmt.Sprintf(format, a...))
+}
+
+// Sprintln 按 fmt.Sprintln 规则拼接参数后应用该样式，重置码会放在换行符之前。
+func (s *Style) Sprintln(a ...interface{}) string {
+	return s.wrap(strings.TrimSuffix(fmt.Sprintln(a...), "\n")) + "\n"
+}
+
+// Print 将 Sprint 的结果写到标准输出。
+func (s *Style) Print(a ...interface{}) (int, error) {
+	return fmt.Print(s.Sprint(a...))
+}
+
+// Println 将 Sprintln 的结果写到标准输出。
+func (s *Style) Println(a ...interface{}) (int, error) {
+	return fmt.Print(s.Sprintln(a...))
+}
+
+// Fprint 将 Sprint 的结果写入 w。
+func (s *Style) Fprint(w io.Writer, a ...interface{}) (int, error) {
+	return fmt.Fprint(w, s.Sprint(a...))
+}
+
+// SprintFunc 返回一个预绑定该样式的闭包，适合在热路径反复调用，
+// 避免每次打印都重新拼接属性前缀。
+func (s *Style) SprintFunc() func(a ...interface{}) string {
+	s.build()
+	return func(a ...interface{}) string { return s.Sprint(a...) }
+}
+
+// SprintfFunc 是 SprintFunc 的 Sprintf 版本。
+func (s *Style) SprintfFunc() func(format string, a ...interface{}) string {
+	s.build()
+	return func(format string, a ...interface{}) string { return s.Sprintf(format, a...) }
+}
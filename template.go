@@ -0,0 +1,205 @@
+package color
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// verbPattern 匹配紧跟在 "%" 之后、构成一个合法 fmt 占位符的部分（标志/
+// 宽度/精度/verb 字母，不含开头的 "%" 本身）。
+var verbPattern = regexp.MustCompile(`^(\[\d+\])?[-+ 0#]*(\d+|\*)?(\.(\d+|\*))?[vVTtbcdoqxXUeEfFgGsp]`)
+
+// escapeStrayPercent 把模板里"不是打算当 Sprintf verb 用"的 "%" 转义成
+// "%%"，使渲染后的模板能安全地当 fmt.Sprintf 的格式串使用。
+//
+// 光看语法无法分辨 "100% done" 里的 "%" 是不是占位符——"% d" 本身就是一个
+// 合法 verb（空格标志 + d），会把 "done" 的 "d" 吃掉变成
+// "%!d(MISSING)one"。这里按 nArgs 依次分配：只有当剩余 args 还够用时才把
+// 匹配到的 "%..." 当成真正的 verb 消耗掉一个参数，否则把这个 "%" 当成字面
+// 文本转义掉，让后面的字符（如 "d"、"one"）保持原样不被吞。
+func escapeStrayPercent(s string, nArgs int) string {
+	if !strings.ContainsRune(s, '%') {
+		return s
+	}
+	var b strings.Builder
+	argIndex := 0
+	i := 0
+	for i < len(s) {
+		if s[i] != '%' {
+			b.WriteByte(s[i])
+			i++
+			continue
+		}
+		if i+1 < len(s) && s[i+1] == '%' {
+			b.WriteString("%%")
+			i += 2
+			continue
+		}
+		if loc := verbPattern.FindStringIndex(s[i+1:]); loc != nil && argIndex < nArgs {
+			b.WriteString(s[i : i+1+loc[1]])
+			i += 1 + loc[1]
+			argIndex++
+			continue
+		}
+		b.WriteString("%%")
+		i++
+	}
+	return b.String()
+}
+
+// namedAttr 是 Sprintft markup 标签到 Attr 的映射表，键统一为小写。
+var namedAttr = map[string]Attr{
+	"black": FgBlack, "red": FgRed, "green": FgGreen, "yellow": FgYellow,
+	"blue": FgBlue, "magenta": FgMagenta, "cyan": FgCyan, "white": FgWhite,
+	"bright-black": FgBrightBlack, "bright-red": FgBrightRed, "bright-green": FgBrightGreen,
+	"bright-yellow": FgBrightYellow, "bright-blue": FgBrightBlue, "bright-magenta": FgBrightMagenta,
+	"bright-cyan": FgBrightCyan, "bright-white": FgBrightWhite,
+
+	"bg-black": BgBlack, "bg-red": BgRed, "bg-green": BgGreen, "bg-yellow": BgYellow,
+	"bg-blue": BgBlue, "bg-magenta": BgMagenta, "bg-cyan": BgCyan, "bg-white": BgWhite,
+	"bg-bright-black": BgBrightBlack, "bg-bright-red": BgBrightRed, "bg-bright-green": BgBrightGreen,
+	"bg-bright-yellow": BgBrightYellow, "bg-bright-blue": BgBrightBlue, "bg-bright-magenta": BgBrightMagenta,
+	"bg-bright-cyan": BgBrightCyan, "bg-bright-white": BgBrightWhite,
+
+	"bold": BoldAttr, "dim": DimAttr, "italic": ItalicAttr, "underline": UnderlineAttr,
+	"blink": BlinkAttr, "inverse": InverseAttr, "hidden": HiddenAttr, "strike": StrikeAttr,
+}
+
+// tagAttr 把 markup 标签里的单个分量（如 "red"、"bold"、"fg-#ff8800"、
+// "bg-202"）转换成对应的 SGR 前缀片段。
+func tagAttr(tok string) (string, error) {
+	tok = strings.TrimSpace(tok)
+	if a, ok := namedAttr[tok]; ok {
+		return SprintAttr(a), nil
+	}
+	switch {
+	case strings.HasPrefix(tok, "fg-#"):
+		r, g, b, err := parseHex(tok[len("fg-"):])
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s38;2;%d;%d;%dm", esc, r, g, b), nil
+	case strings.HasPrefix(tok, "bg-#"):
+		r, g, b, err := parseHex(tok[len("bg-"):])
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s48;2;%d;%d;%dm", esc, r, g, b), nil
+	case strings.HasPrefix(tok, "fg-"):
+		idx, err := strconv.Atoi(tok[len("fg-"):])
+		if err != nil {
+			return "", fmt.Errorf("color: unknown template tag %q", tok)
+		}
+		return fmt.Sprintf("%s38;5;%dm", esc, idx), nil
+	case strings.HasPrefix(tok, "bg-"):
+		idx, err := strconv.Atoi(tok[len("bg-"):])
+		if err != nil {
+			return "", fmt.Errorf("color: unknown template tag %q", tok)
+		}
+		return fmt.Sprintf("%s48;5;%dm", esc, idx), nil
+	}
+	return "", fmt.Errorf("color: unknown template tag %q", tok)
+}
+
+// tplSegment 是模板解析后的一个片段：先原样输出 literal（可能带 %v 占位符），
+// 再输出 code（标签对应的 SGR 序列，或 pop 标签时 reset+外层标签的回溯）。
+type tplSegment struct {
+	literal string
+	code    string
+}
+
+// parseTemplate 解析 `<red>error:</> {count=%d} <bold,bg-blue>hi</>` 这样的
+// markup 模板，用一个标签栈维护当前生效样式：`</>` 弹出最近一个标签，
+// 通过重新输出 reset + 剩余栈内标签，让外层样式（如 `<red>a <bold>b</> c</>`
+// 中的 red）在内层标签结束后继续生效。
+func parseTemplate(tmpl string) ([]tplSegment, error) {
+	var segs []tplSegment
+	var stack []string
+	var lit strings.Builder
+
+	flush := func(code string) {
+		segs = append(segs, tplSegment{literal: lit.String(), code: code})
+		lit.Reset()
+	}
+
+	i := 0
+	for i < len(tmpl) {
+		if tmpl[i] != '<' {
+			lit.WriteByte(tmpl[i])
+			i++
+			continue
+		}
+		end := strings.IndexByte(tmpl[i:], '>')
+		if end < 0 {
+			lit.WriteByte(tmpl[i])
+			i++
+			continue
+		}
+		tag := tmpl[i+1 : i+end]
+		i += end + 1
+
+		if tag == "/" {
+			if len(stack) == 0 {
+				continue
+			}
+			stack = stack[:len(stack)-1]
+			flush(reset + strings.Join(stack, ""))
+			continue
+		}
+
+		var prefix strings.Builder
+		for _, part := range strings.Split(tag, ",") {
+			p, err := tagAttr(part)
+			if err != nil {
+				return nil, err
+			}
+			prefix.WriteString(p)
+		}
+		stack = append(stack, prefix.String())
+		flush(prefix.String())
+	}
+	if lit.Len() > 0 || len(segs) == 0 {
+		segs = append(segs, tplSegment{literal: lit.String()})
+	}
+	return segs, nil
+}
+
+func renderTemplate(segs []tplSegment) string {
+	var b strings.Builder
+	for _, seg := range segs {
+		b.WriteString(seg.literal)
+		if enabled.Load() {
+			b.WriteString(seg.code)
+		}
+	}
+	return b.String()
+}
+
+// Sprintft 解析 markup 模板并用 fmt.Sprintf 规则代入 args，例如：
+//
+//	color.Sprintft("<red>error:</> {count=%d}", 3)
+//
+// 标签对应已有的 Attr 常量（颜色名、bold/underline 等），也支持
+// `fg-#ff8800`、`bg-202` 这类十六进制/256色写法；`</>` 弹出最近一个标签。
+func Sprintft(template string, args ...interface{}) string {
+	segs, err := parseTemplate(template)
+	if err != nil {
+		return template
+	}
+	return fmt.Sprintf(escapeStrayPercent(renderTemplate(segs), len(args)), args...)
+}
+
+// MustCompileTemplate 预先解析模板为字面量/属性片段，返回一个可反复调用的
+// 闭包，避免热路径上每次打印都重新解析 markup。模板非法（如未知标签）时
+// panic。
+func MustCompileTemplate(tmpl string) func(args ...interface{}) string {
+	segs, err := parseTemplate(tmpl)
+	if err != nil {
+		panic(err)
+	}
+	return func(args ...interface{}) string {
+		return fmt.Sprintf(escapeStrayPercent(renderTemplate(segs), len(args)), args...)
+	}
+}
@@ -0,0 +1,49 @@
+package color
+
+import "testing"
+
+func TestParseTemplateStack(t *testing.T) {
+	segs, err := parseTemplate("<red>a <bold>b</> c</>")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(segs) != 4 {
+		t.Fatalf("got %d segments, want 4: %+v", len(segs), segs)
+	}
+	// popping "bold" must restore "red", not reset to nothing.
+	if segs[2].code != reset+segs[0].code {
+		t.Fatalf("pop segment = %q, want reset+outer red prefix", segs[2].code)
+	}
+}
+
+func TestParseTemplateUnknownTag(t *testing.T) {
+	if _, err := parseTemplate("<not-a-tag>x</>"); err == nil {
+		t.Fatal("expected error for unknown tag")
+	}
+}
+
+func TestSprintftStrayPercent(t *testing.T) {
+	Disable()
+	defer Enable()
+	if got := Sprintft("100% done"); got != "100% done" {
+		t.Fatalf("got %q, want %q", got, "100% done")
+	}
+}
+
+func TestSprintftVerbAndLiteralPercent(t *testing.T) {
+	Disable()
+	defer Enable()
+	got := Sprintft("<red>%d%% done (%s)</>", 50, "ok")
+	if got != "50% done (ok)" {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestMustCompileTemplatePanicsOnUnknownTag(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic for unknown tag")
+		}
+	}()
+	MustCompileTemplate("<bogus>x</>")
+}
@@ -0,0 +1,149 @@
+package color
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// sgrSeqLen 判断 s 开头是否是一个 "\x1b[...m" 形式的 SGR 转义序列，
+// 返回其字节长度；不是则返回 0。
+func sgrSeqLen(s string) int {
+	if len(s) < 3 || s[0] != 0x1b || s[1] != '[' {
+		return 0
+	}
+	for i := 2; i < len(s); i++ {
+		c := s[i]
+		if c == 'm' {
+			return i + 1
+		}
+		if !(c == ';' || (c >= '0' && c <= '9')) {
+			return 0
+		}
+	}
+	return 0
+}
+
+// trackSGR 根据新遇到的 SGR 序列更新“当前生效样式”：遇到重置码清空，
+// 否则直接替换为最新序列（足以覆盖本包自身 Wrap/Style 产出的单段样式，
+// 不做多属性合并）。
+func trackSGR(active, seq string) string {
+	if seq == reset {
+		return ""
+	}
+	return seq
+}
+
+// runeWidth 返回一个字符在终端里占的列数：控制字符计 0，常见 CJK/emoji
+// 宽字符计 2，其余计 1。
+func runeWidth(r rune) int {
+	if r < 0x20 || (r >= 0x7f && r < 0xa0) {
+		return 0
+	}
+	switch {
+	case r >= 0x1100 && r <= 0x115F, // Hangul Jamo
+		r == 0x2329 || r == 0x232A,
+		r >= 0x2E80 && r <= 0xA4CF && r != 0x303F, // CJK 部首/符号/表意文字
+		r >= 0xAC00 && r <= 0xD7A3,                // Hangul 音节
+		r >= 0xF900 && r <= 0xFAFF,                // CJK 兼容表意文字
+		r >= 0xFF00 && r <= 0xFF60,                // 全角形式
+		r >= 0xFFE0 && r <= 0xFFE6,
+		r >= 0x1F300 && r <= 0x1FAFF, // emoji 区段
+		r >= 0x20000 && r <= 0x3FFFD: // CJK 扩展区
+		return 2
+	default:
+		return 1
+	}
+}
+
+// VisibleLen 返回 s 去除 SGR 转义序列后的可见宽度（宽字符如 CJK/emoji 计 2 列）。
+func VisibleLen(s string) int {
+	width := 0
+	i := 0
+	for i < len(s) {
+		if n := sgrSeqLen(s[i:]); n > 0 {
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		width += runeWidth(r)
+		i += size
+	}
+	return width
+}
+
+// Truncate 把 s 截断到 n 个可见列，期间保留所有经过的 SGR 转义序列，
+// 并在截断处追加一个 Reset，避免颜色在截断点之后继续生效。
+func Truncate(s string, n int) string {
+	if n <= 0 {
+		return ""
+	}
+	var b strings.Builder
+	width := 0
+	active := ""
+	i := 0
+	for i < len(s) {
+		if sl := sgrSeqLen(s[i:]); sl > 0 {
+			seq := s[i : i+sl]
+			active = trackSGR(active, seq)
+			b.WriteString(seq)
+			i += sl
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if w := runeWidth(r); width+w > n {
+			break
+		} else {
+			width += w
+		}
+		b.WriteRune(r)
+		i += size
+	}
+	if active != "" {
+		b.WriteString(reset)
+	}
+	return b.String()
+}
+
+// WrapWidth 在第 width 列处硬折行，并在每个续行开头重新输出当前生效的 SGR
+// 属性，使颜色不会因为换行而丢失。之所以叫 WrapWidth 而不是 Wrap，是因为
+// Wrap(s string, attrs ...Attr) 这个名字已经被属性包裹函数占用了。
+func WrapWidth(s string, width int) string {
+	if width <= 0 {
+		return s
+	}
+	var b strings.Builder
+	col := 0
+	active := ""
+	i := 0
+	for i < len(s) {
+		if sl := sgrSeqLen(s[i:]); sl > 0 {
+			seq := s[i : i+sl]
+			active = trackSGR(active, seq)
+			b.WriteString(seq)
+			i += sl
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == '\n' {
+			b.WriteRune(r)
+			col = 0
+			i += size
+			continue
+		}
+		w := runeWidth(r)
+		if col+w > width {
+			if active != "" {
+				b.WriteString(reset)
+			}
+			b.WriteByte('\n')
+			if active != "" {
+				b.WriteString(active)
+			}
+			col = 0
+		}
+		b.WriteRune(r)
+		col += w
+		i += size
+	}
+	return b.String()
+}
@@ -0,0 +1,52 @@
+package color
+
+import "testing"
+
+func TestVisibleLen(t *testing.T) {
+	cases := []struct {
+		in   string
+		want int
+	}{
+		{"abc", 3},
+		{esc + "31m" + "abc" + reset, 3},
+		{"中文", 4},
+		{"", 0},
+	}
+	for _, c := range cases {
+		if got := VisibleLen(c.in); got != c.want {
+			t.Errorf("VisibleLen(%q) = %d, want %d", c.in, got, c.want)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got := Truncate("hello", 3); got != "hel" {
+		t.Errorf("Truncate = %q, want %q", got, "hel")
+	}
+	if got := Truncate("hello", 10); got != "hello" {
+		t.Errorf("Truncate = %q, want %q", got, "hello")
+	}
+	colored := esc + "31m" + "hello" + reset
+	got := Truncate(colored, 3)
+	want := esc + "31m" + "hel" + reset
+	if got != want {
+		t.Errorf("Truncate(%q, 3) = %q, want %q", colored, got, want)
+	}
+}
+
+func TestWrapWidth(t *testing.T) {
+	got := WrapWidth("abcdef", 3)
+	want := "abc\ndef"
+	if got != want {
+		t.Errorf("WrapWidth = %q, want %q", got, want)
+	}
+}
+
+func TestFindSGREndStylePortable(t *testing.T) {
+	if n := sgrSeqLen(esc + "1;31m" + "x"); n != len(esc+"1;31m") {
+		t.Errorf("sgrSeqLen = %d, want %d", n, len(esc+"1;31m"))
+	}
+	if n := sgrSeqLen("plain"); n != 0 {
+		t.Errorf("sgrSeqLen(plain) = %d, want 0", n)
+	}
+}
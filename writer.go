@@ -0,0 +1,28 @@
+package color
+
+import (
+	"io"
+	"os"
+)
+
+// NewWriter 包装一个 io.Writer。
+//
+// 在 Windows 且控制台不支持 ANSI 转义（未开启 VT 处理）时，返回的 Writer 会
+// 解析本包产生的 SGR 转义序列并转换为等价的 SetConsoleTextAttribute 调用；
+// 在类 Unix 系统、以及已开启 VT 处理的 Windows 10+ 控制台下，字节将原样透传。
+//
+// Disable() 之后写入的数据同样原样透传，不做任何解析。
+func NewWriter(w io.Writer) io.Writer {
+	return newWriter(w)
+}
+
+var (
+	stdoutWriter = NewWriter(os.Stdout)
+	stderrWriter = NewWriter(os.Stderr)
+)
+
+// Stdout 返回经 NewWriter 包装后的标准输出，适合跨平台直接使用。
+func Stdout() io.Writer { return stdoutWriter }
+
+// Stderr 返回经 NewWriter 包装后的标准错误输出，适合跨平台直接使用。
+func Stderr() io.Writer { return stderrWriter }
@@ -0,0 +1,10 @@
+//go:build !windows
+
+package color
+
+import "io"
+
+// newWriter 在非 Windows 平台上原样透传，终端自身已经理解 ANSI 转义。
+func newWriter(w io.Writer) io.Writer {
+	return w
+}
@@ -0,0 +1,309 @@
+//go:build windows
+
+package color
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"golang.org/x/sys/windows"
+)
+
+// x/sys/windows 没有导出 CONSOLE_SCREEN_BUFFER_INFO.wAttributes 里用到的
+// FOREGROUND_*/BACKGROUND_* 位掩码，也没有包一层 SetConsoleTextAttribute，
+// 这里按 Windows Console API 文档里的原始数值自行声明。
+const (
+	winFgBlack     = 0
+	winFgBlue      = 0x0001
+	winFgGreen     = 0x0002
+	winFgRed       = 0x0004
+	winFgIntensity = 0x0008
+	winBgBlack     = 0
+	winBgBlue      = 0x0010
+	winBgGreen     = 0x0020
+	winBgRed       = 0x0040
+	winBgIntensity = 0x0080
+
+	winDefaultAttr = winFgRed | winFgGreen | winFgBlue // 灰色前景，黑色背景
+)
+
+var (
+	modkernel32                 = windows.NewLazySystemDLL("kernel32.dll")
+	procSetConsoleTextAttribute = modkernel32.NewProc("SetConsoleTextAttribute")
+)
+
+// setConsoleTextAttribute 是 x/sys/windows 缺失的 SetConsoleTextAttribute
+// 系统调用封装，做法与 mattn/go-colorable 一致：通过 NewLazySystemDLL 直接
+// 绑定 kernel32 导出函数。
+func setConsoleTextAttribute(h windows.Handle, attr uint16) error {
+	r1, _, e1 := procSetConsoleTextAttribute.Call(uintptr(h), uintptr(attr))
+	if r1 == 0 {
+		return e1
+	}
+	return nil
+}
+
+// ansiFgAttr 将 SGR 30-37/90-97 前景色映射到 windows 控制台属性位。
+var ansiFgAttr = [8]uint16{
+	winFgBlack,
+	winFgRed,
+	winFgGreen,
+	winFgRed | winFgGreen,
+	winFgBlue,
+	winFgRed | winFgBlue,
+	winFgGreen | winFgBlue,
+	winFgRed | winFgGreen | winFgBlue,
+}
+
+var ansiBgAttr = [8]uint16{
+	winBgBlack,
+	winBgRed,
+	winBgGreen,
+	winBgRed | winBgGreen,
+	winBgBlue,
+	winBgRed | winBgBlue,
+	winBgGreen | winBgBlue,
+	winBgRed | winBgGreen | winBgBlue,
+}
+
+// ansiWriter 把写入的字节中夹杂的 SGR 转义序列转换为 SetConsoleTextAttribute
+// 调用，供不支持 ANSI 的 cmd.exe / 旧版 conhost 使用。
+type ansiWriter struct {
+	mu      sync.Mutex
+	w       io.Writer
+	handle  windows.Handle
+	isCon   bool
+	attr    uint16 // 当前生效的控制台属性
+	pending []byte // 跨 Write 调用缓冲的不完整转义序列
+}
+
+func newWriter(w io.Writer) io.Writer {
+	aw := &ansiWriter{w: w, attr: winDefaultAttr}
+	if h, ok := consoleHandle(w); ok {
+		var mode uint32
+		if err := windows.GetConsoleMode(h, &mode); err == nil {
+			aw.handle = h
+			aw.isCon = true
+			// Windows 10+ 且已开启 VT 处理时无需转换，直接透传。
+			if mode&windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING != 0 {
+				return w
+			}
+			_ = setConsoleTextAttribute(h, winDefaultAttr)
+		}
+	}
+	if !aw.isCon {
+		// 不是控制台句柄（比如被重定向到文件/管道），无需解析，原样透传。
+		return w
+	}
+	return aw
+}
+
+func (a *ansiWriter) Write(p []byte) (int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if !enabled.Load() {
+		// Disable() 之后短路为透传，不做任何解析。
+		return a.w.Write(p)
+	}
+
+	written := len(p)
+	buf := append(a.pending, p...)
+	a.pending = nil
+
+	for {
+		start := bytes.IndexByte(buf, 0x1b)
+		if start < 0 {
+			if _, err := a.w.Write(buf); err != nil {
+				return 0, err
+			}
+			return written, nil
+		}
+		if _, err := a.w.Write(buf[:start]); err != nil {
+			return 0, err
+		}
+		buf = buf[start:]
+
+		end, isSGR := findSGREnd(buf)
+		if end < 0 {
+			// 转义序列被截断在本次 Write 的末尾，留到下次拼接。
+			a.pending = append([]byte(nil), buf...)
+			return written, nil
+		}
+		if isSGR {
+			a.applySGR(buf[:end])
+		} else if _, err := a.w.Write(buf[:end]); err != nil {
+			return 0, err
+		}
+		buf = buf[end:]
+	}
+}
+
+// findSGREnd 返回 buf 开头一段转义序列的长度。如果是一个完整的
+// "\x1b[...m" SGR 序列，isSGR 为 true，end 含终止符 m。如果 buf[0:2] 是
+// "\x1b[" 但后面跟着非 SGR 字节（比如 "\x1b[?25h"），放弃解析 SGR 语法，
+// 返回 end=1、isSGR=false，让调用方把这一个字节原样写出；"\x1b" 后面不是
+// '[' 时同样返回 end=1、isSGR=false。如果 buf 里还看不到终止符，返回
+// end=-1，表示序列被切分了，留到下次拼接。
+func findSGREnd(buf []byte) (end int, isSGR bool) {
+	if len(buf) < 2 {
+		return -1, false
+	}
+	if buf[0] != 0x1b || buf[1] != '[' {
+		return 1, false
+	}
+	for i := 2; i < len(buf); i++ {
+		c := buf[i]
+		if c == 'm' {
+			return i + 1, true
+		}
+		if !(c == ';' || (c >= '0' && c <= '9')) {
+			return 1, false
+		}
+	}
+	return -1, false
+}
+
+func (a *ansiWriter) applySGR(seq []byte) {
+	body := string(seq[2 : len(seq)-1])
+	if body == "" {
+		body = "0"
+	}
+	parts := strings.Split(body, ";")
+	nums := make([]int, len(parts))
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			n = 0
+		}
+		nums[i] = n
+	}
+	for i := 0; i < len(nums); i++ {
+		n := nums[i]
+		if (n == 38 || n == 48) && i+1 < len(nums) {
+			fg := n == 38
+			switch nums[i+1] {
+			case 5: // 38;5;idx or 48;5;idx
+				if i+2 < len(nums) {
+					a.applyNearest16(fg, index256ToRGB(nums[i+2]))
+					i += 2
+				}
+			case 2: // 38;2;r;g;b or 48;2;r;g;b
+				if i+4 < len(nums) {
+					a.applyNearest16(fg, [3]int{nums[i+2], nums[i+3], nums[i+4]})
+					i += 4
+				}
+			}
+			continue
+		}
+		a.applyCode(n)
+	}
+	_ = setConsoleTextAttribute(a.handle, a.attr)
+}
+
+// applyNearest16 把一个 256色/真彩色值降级为最接近的 16 色控制台属性。
+func (a *ansiWriter) applyNearest16(fg bool, rgb [3]int) {
+	code, bright := nearestConsole16(rgb)
+	if fg {
+		a.attr = a.attr &^ (winFgRed | winFgGreen | winFgBlue | winFgIntensity)
+		a.attr |= ansiFgAttr[code]
+		if bright {
+			a.attr |= winFgIntensity
+		}
+	} else {
+		a.attr = a.attr &^ (winBgRed | winBgGreen | winBgBlue | winBgIntensity)
+		a.attr |= ansiBgAttr[code]
+		if bright {
+			a.attr |= winBgIntensity
+		}
+	}
+}
+
+// nearestConsole16 返回 rgb 最接近的 8 色基础色下标（0-7）以及是否应叠加高亮位。
+func nearestConsole16(rgb [3]int) (code int, bright bool) {
+	best, bestDist := 0, -1
+	for i := 0; i < 8; i++ {
+		r := (i & 1) * 255
+		g := (i >> 1 & 1) * 255
+		b := (i >> 2 & 1) * 255
+		d := sq(rgb[0]-r) + sq(rgb[1]-g) + sq(rgb[2]-b)
+		if bestDist < 0 || d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	// 基础色分量之和越高，越倾向于叠加高亮位而不是直接判定为白色。
+	bright = rgb[0]+rgb[1]+rgb[2] > 255*3/2
+	return best, bright
+}
+
+func sq(v int) int { return v * v }
+
+// index256ToRGB 把 xterm 256色调色板下标还原为近似 RGB，供降级使用。
+func index256ToRGB(idx int) [3]int {
+	if idx < 0 {
+		idx = 0
+	}
+	if idx > 255 {
+		idx = 255
+	}
+	switch {
+	case idx < 16:
+		// 0-15 对应标准/高亮 16 色，直接用标准 ANSI 前景码表近似。
+		levels := [16][3]int{
+			{0, 0, 0}, {128, 0, 0}, {0, 128, 0}, {128, 128, 0},
+			{0, 0, 128}, {128, 0, 128}, {0, 128, 128}, {192, 192, 192},
+			{128, 128, 128}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+			{0, 0, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+		}
+		return levels[idx]
+	case idx < 232:
+		idx -= 16
+		steps := [6]int{0, 95, 135, 175, 215, 255}
+		r := steps[idx/36]
+		g := steps[(idx/6)%6]
+		b := steps[idx%6]
+		return [3]int{r, g, b}
+	default:
+		v := 8 + 10*(idx-232)
+		return [3]int{v, v, v}
+	}
+}
+
+func (a *ansiWriter) applyCode(n int) {
+	switch {
+	case n == 0:
+		a.attr = winDefaultAttr
+	case n == 1:
+		a.attr |= winFgIntensity
+	case n >= 30 && n <= 37:
+		a.attr = a.attr&^(winFgRed|winFgGreen|winFgBlue) | ansiFgAttr[n-30]
+	case n == 39:
+		a.attr = a.attr&^(winFgRed|winFgGreen|winFgBlue) | (winDefaultAttr & (winFgRed | winFgGreen | winFgBlue))
+	case n >= 40 && n <= 47:
+		a.attr = a.attr&^(winBgRed|winBgGreen|winBgBlue) | ansiBgAttr[n-40]
+	case n == 49:
+		a.attr = a.attr &^ (winBgRed | winBgGreen | winBgBlue)
+	case n >= 90 && n <= 97:
+		a.attr = a.attr&^(winFgRed|winFgGreen|winFgBlue) | ansiFgAttr[n-90] | winFgIntensity
+	case n >= 100 && n <= 107:
+		a.attr = a.attr&^(winBgRed|winBgGreen|winBgBlue) | ansiBgAttr[n-100] | winBgIntensity
+	}
+	// 256色/真彩前景(38;5;n / 38;2;r;g;b)与背景(48;...)由 applySGR 通过
+	// applyNearest16 降级处理，不会走到这里；其余未识别的代码直接忽略，
+	// 保留当前属性不变。
+}
+
+// consoleHandle 尝试从 io.Writer 取出底层的控制台句柄。
+func consoleHandle(w io.Writer) (windows.Handle, bool) {
+	type fdWriter interface {
+		Fd() uintptr
+	}
+	fw, ok := w.(fdWriter)
+	if !ok {
+		return 0, false
+	}
+	return windows.Handle(fw.Fd()), true
+}
@@ -0,0 +1,69 @@
+//go:build windows
+
+package color
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestFindSGREnd(t *testing.T) {
+	cases := []struct {
+		in      string
+		wantEnd int
+		wantSGR bool
+	}{
+		{"\x1b[31m", 5, true},
+		{"\x1b[1;31mrest", 7, true},
+		{"\x1b[", -1, false},
+		{"\x1b[31", -1, false},
+		{"\x1b[?25h", 1, false},
+	}
+	for _, c := range cases {
+		end, isSGR := findSGREnd([]byte(c.in))
+		if end != c.wantEnd || isSGR != c.wantSGR {
+			t.Errorf("findSGREnd(%q) = (%d, %v), want (%d, %v)", c.in, end, isSGR, c.wantEnd, c.wantSGR)
+		}
+	}
+}
+
+// A non-SGR CSI sequence (e.g. cursor-visibility "\x1b[?25h") must be
+// passed through untouched instead of panicking in applySGR.
+func TestAnsiWriterNonSGREscapePassthrough(t *testing.T) {
+	var buf bytes.Buffer
+	aw := &ansiWriter{w: &buf, attr: winDefaultAttr}
+	if _, err := aw.Write([]byte("hi\x1b[?25hbye")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := buf.String(); got != "hi\x1b[?25hbye" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestAnsiWriterAppliesSGR(t *testing.T) {
+	var buf bytes.Buffer
+	aw := &ansiWriter{w: &buf, attr: winDefaultAttr}
+	if _, err := aw.Write([]byte("\x1b[31mred\x1b[0m")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := buf.String(); got != "red" {
+		t.Errorf("got %q, want SGR codes stripped", got)
+	}
+}
+
+func TestNearestConsole16(t *testing.T) {
+	cases := []struct {
+		rgb  [3]int
+		want uint16
+	}{
+		{[3]int{255, 0, 0}, winFgRed},
+		{[3]int{0, 0, 255}, winFgBlue},
+		{[3]int{0, 255, 0}, winFgGreen},
+	}
+	for _, c := range cases {
+		code, _ := nearestConsole16(c.rgb)
+		if got := ansiFgAttr[code]; got != c.want {
+			t.Errorf("nearestConsole16(%v) -> ansiFgAttr[%d] = %#x, want %#x", c.rgb, code, got, c.want)
+		}
+	}
+}